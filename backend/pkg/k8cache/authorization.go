@@ -22,26 +22,16 @@ package k8cache
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
-	"sync"
-	"time"
 
-	"github.com/gorilla/mux"
 	"github.com/kubernetes-sigs/headlamp/backend/pkg/kubeconfig"
-	"github.com/kubernetes-sigs/headlamp/backend/pkg/logger"
 	"k8s.io/client-go/kubernetes"
 )
 
-type CachedClientSet struct {
-	clientset *kubernetes.Clientset
-	lastUsed  time.Time
-}
-
-var (
-	clientsetCache = make(map[string]*CachedClientSet)
-	mu             sync.Mutex
-)
+// clientsetCache is the bounded, expiring store backing GetClientSet. It
+// replaces the old unbounded map that was only cleaned up on access; see
+// ClientSetCache in clientsetcache.go for the eviction policy.
+var clientsetCache = NewClientSetCache(ClientSetCacheOptions{}) //nolint:gochecknoglobals
 
 // GetClientSet return *kubernetes.ClientSet and error which further used for creating
 // SSAR requests to k8s server to authorize user. GetClientSet uses kubeconfig.Context and
@@ -55,19 +45,8 @@ func GetClientSet(k *kubeconfig.Context, token string) (*kubernetes.Clientset, e
 
 	cacheKey := fmt.Sprintf("%s-%s", contextKey[1], token)
 
-	mu.Lock()
-	defer mu.Unlock()
-
-	if cs, found := clientsetCache[cacheKey]; found {
-		now := time.Now()
-
-		if now.Sub(cs.lastUsed) > 10*time.Minute { // If the clientset was expired then delete
-			// the existing clientset resulting only fresh clientset.
-			delete(clientsetCache, cacheKey)
-			logger.Log(logger.LevelInfo, nil, nil, "clientset "+cacheKey+" was deleted")
-		} else {
-			return cs.clientset, nil // If the clientset is not expired then return directly.
-		}
+	if cs, found := clientsetCache.Get(cacheKey); found {
+		return cs, nil
 	}
 
 	cs, err := k.ClientSetWithToken(token)
@@ -75,39 +54,17 @@ func GetClientSet(k *kubeconfig.Context, token string) (*kubernetes.Clientset, e
 		return nil, fmt.Errorf("error while creating clientset for key %s: %w", cacheKey, err)
 	}
 
-	clientsetCache[cacheKey] = &CachedClientSet{
-		clientset: cs,
-		lastUsed:  time.Now(),
-	}
+	clientsetCache.Set(cacheKey, cs)
 
 	return cs, nil
 }
 
-// GetKindAndVerb extracts the Kubernetes resource kind and intended verb (e.g., get, watch)
-// from the incoming HTTP request.
+// GetKindAndVerb extracts the Kubernetes resource and intended verb (e.g., get, watch)
+// from the incoming HTTP request. It is kept for callers that only need the resource
+// name; GetRequestInfo returns the full parsed RequestInfo that the cache key builder
+// and invalidation hooks use.
 func GetKindAndVerb(r *http.Request) (string, string) {
-	apiPath, ok := mux.Vars(r)["api"]
-	if !ok || apiPath == "" {
-		return "", "unknown"
-	}
-
-	parts := strings.Split(apiPath, "/")
-	last := parts[len(parts)-1]
-
-	var kubeVerb string
-
-	isWatch, _ := strconv.ParseBool(r.URL.Query().Get("watch"))
-
-	switch r.Method {
-	case "GET":
-		if isWatch {
-			kubeVerb = "watch"
-		} else {
-			kubeVerb = "get"
-		}
-	default:
-		kubeVerb = "unknown"
-	}
+	info := GetRequestInfo(r)
 
-	return last, kubeVerb
+	return info.Resource, info.Verb
 }
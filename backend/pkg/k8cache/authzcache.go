@@ -0,0 +1,373 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/singleflight"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// authzCacheShards is the number of shards AuthzCache splits its entries
+// across, the same way ClientSetCache does, so lookups for different keys
+// don't serialize on one lock. Its keyspace (token x verb x GVR x
+// namespace x name) has far higher cardinality than ClientSetCache's, so
+// sharding matters more here, not less.
+const authzCacheShards = 16
+
+const (
+	defaultAuthzPositiveTTL   = 30 * time.Second
+	defaultAuthzNegativeTTL   = 5 * time.Second
+	defaultAuthzMaxEntries    = 50000
+	defaultAuthzSweepInterval = time.Minute
+)
+
+var ( //nolint:gochecknoglobals
+	authzCacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "headlamp_authz_cache_hits_total",
+		Help: "Number of SubjectAccessReview lookups served from the authz cache.",
+	})
+	authzCacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "headlamp_authz_cache_misses_total",
+		Help: "Number of SubjectAccessReview lookups that required an SSAR call.",
+	})
+	ssarLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "headlamp_ssar_duration_seconds",
+		Help:    "Latency of SubjectAccessReview calls issued on an authz cache miss.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+// AuthzDecision is a cached SubjectAccessReview outcome.
+type AuthzDecision struct {
+	Allowed   bool
+	Reason    string
+	ExpiresAt time.Time
+}
+
+// SSARFunc issues the actual SubjectAccessReview call. It is injected so
+// AuthzCache doesn't need to know how to build a clientset.
+type SSARFunc func(token string, attrs authorizationv1.ResourceAttributes) (allowed bool, reason string, err error)
+
+// authzCacheEntry is a decision plus its position in the owning shard's LRU
+// list, mirroring clientSetCacheEntry in clientsetcache.go.
+type authzCacheEntry struct {
+	decision AuthzDecision
+	element  *list.Element // position in the shard's LRU list
+}
+
+// authzCacheShard is one lock-striped, bounded slice of the cache, sized
+// and evicted the same way clientSetCacheShard is.
+type authzCacheShard struct {
+	mu      sync.RWMutex
+	entries map[string]*authzCacheEntry
+	lru     *list.List // front = most recently used; back = least
+}
+
+// AuthzCache sits in front of SSAR calls so that repeated authorization
+// checks for the same (user, verb, GVR, namespace, name) within the
+// positive/negative TTL don't re-issue an SSAR. Concurrent lookups for the
+// same key are deduped with singleflight so only one SSAR is ever in
+// flight for it at a time. Entries are bounded per shard by an LRU policy
+// and swept for expiry by a single background goroutine, the same way
+// ClientSetCache is in clientsetcache.go.
+type AuthzCache struct {
+	shards      [authzCacheShards]*authzCacheShard
+	group       singleflight.Group
+	issueSSAR   SSARFunc
+	maxEntries  int
+	positiveTTL time.Duration
+	negativeTTL time.Duration
+	clock       Clock
+	stopCh      chan struct{}
+}
+
+// AuthzCacheOptions configures NewAuthzCache.
+type AuthzCacheOptions struct {
+	PositiveTTL time.Duration
+	NegativeTTL time.Duration
+	// MaxEntries is the maximum number of decisions held per shard before
+	// the least-recently-used entry in that shard is evicted.
+	MaxEntries int
+	// SweepInterval is how often the background goroutine scans for
+	// expired entries.
+	SweepInterval time.Duration
+	// Clock is used for TTL bookkeeping; defaults to the real wall clock.
+	Clock Clock
+}
+
+// NewAuthzCache creates an AuthzCache that issues SSARs via issueSSAR and
+// starts its background sweep goroutine.
+func NewAuthzCache(issueSSAR SSARFunc, opts AuthzCacheOptions) *AuthzCache {
+	if opts.PositiveTTL <= 0 {
+		opts.PositiveTTL = defaultAuthzPositiveTTL
+	}
+
+	if opts.NegativeTTL <= 0 {
+		opts.NegativeTTL = defaultAuthzNegativeTTL
+	}
+
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultAuthzMaxEntries
+	}
+
+	if opts.SweepInterval <= 0 {
+		opts.SweepInterval = defaultAuthzSweepInterval
+	}
+
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+
+	c := &AuthzCache{
+		issueSSAR:   issueSSAR,
+		maxEntries:  opts.MaxEntries,
+		positiveTTL: opts.PositiveTTL,
+		negativeTTL: opts.NegativeTTL,
+		clock:       opts.Clock,
+		stopCh:      make(chan struct{}),
+	}
+
+	for i := range c.shards {
+		c.shards[i] = &authzCacheShard{
+			entries: make(map[string]*authzCacheEntry),
+			lru:     list.New(),
+		}
+	}
+
+	go c.runSweeper(opts.SweepInterval)
+
+	return c
+}
+
+// Stop halts the background sweep goroutine.
+func (c *AuthzCache) Stop() {
+	close(c.stopCh)
+}
+
+func (c *AuthzCache) shardFor(key string) *authzCacheShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return c.shards[h.Sum32()%authzCacheShards]
+}
+
+// authzKey builds the cache key for a token + resource attributes tuple.
+// The token itself is hashed so it never ends up in memory as plain text
+// or leaks through logs of the cache.
+func authzKey(token string, attrs authorizationv1.ResourceAttributes) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return strings.Join([]string{
+		hex.EncodeToString(sum[:]),
+		attrs.Verb,
+		attrs.Group, attrs.Resource, attrs.Subresource,
+		attrs.Namespace, attrs.Name,
+	}, "|")
+}
+
+// Allowed returns whether the request is authorized, serving a cached
+// decision when one is live, and otherwise issuing (or joining an in-flight)
+// SSAR call and caching its result for PositiveTTL or NegativeTTL depending
+// on the outcome.
+func (c *AuthzCache) Allowed(token string, attrs authorizationv1.ResourceAttributes) (bool, string, error) {
+	key := authzKey(token, attrs)
+
+	if decision, found := c.get(key); found {
+		authzCacheHits.Inc()
+		return decision.Allowed, decision.Reason, nil
+	}
+
+	authzCacheMisses.Inc()
+
+	result, err, _ := c.group.Do(key, func() (interface{}, error) {
+		start := time.Now()
+		allowed, reason, err := c.issueSSAR(token, attrs)
+		ssarLatency.Observe(time.Since(start).Seconds())
+
+		if err != nil {
+			return nil, err
+		}
+
+		ttl := c.negativeTTL
+		if allowed {
+			ttl = c.positiveTTL
+		}
+
+		decision := AuthzDecision{Allowed: allowed, Reason: reason, ExpiresAt: c.clock.Now().Add(ttl)}
+		c.set(key, decision)
+
+		return decision, nil
+	})
+	if err != nil {
+		return false, "", fmt.Errorf("error issuing SubjectAccessReview: %w", err)
+	}
+
+	decision, ok := result.(AuthzDecision) //nolint:forcetypeassert
+	if !ok {
+		return false, "", fmt.Errorf("unexpected singleflight result type %T", result)
+	}
+
+	return decision.Allowed, decision.Reason, nil
+}
+
+func (c *AuthzCache) get(key string) (AuthzDecision, bool) {
+	shard := c.shardFor(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	entry, found := shard.entries[key]
+	if !found || c.clock.Now().After(entry.decision.ExpiresAt) {
+		return AuthzDecision{}, false
+	}
+
+	shard.lru.MoveToFront(entry.element)
+
+	return entry.decision, true
+}
+
+func (c *AuthzCache) set(key string, decision AuthzDecision) {
+	shard := c.shardFor(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if existing, found := shard.entries[key]; found {
+		existing.decision = decision
+		shard.lru.MoveToFront(existing.element)
+
+		return
+	}
+
+	element := shard.lru.PushFront(key)
+	shard.entries[key] = &authzCacheEntry{decision: decision, element: element}
+
+	if shard.lru.Len() > c.maxEntries {
+		oldest := shard.lru.Back()
+		shard.lru.Remove(oldest)
+		delete(shard.entries, oldest.Value.(string)) //nolint:forcetypeassert
+	}
+}
+
+// FlushToken evicts every cached decision for token, used when the token's
+// claims change (e.g. the user's group membership was refreshed).
+func (c *AuthzCache) FlushToken(token string) {
+	sum := sha256.Sum256([]byte(token))
+	prefix := hex.EncodeToString(sum[:]) + "|"
+
+	for _, shard := range c.shards {
+		shard.mu.Lock()
+
+		for key, entry := range shard.entries {
+			if strings.HasPrefix(key, prefix) {
+				shard.lru.Remove(entry.element)
+				delete(shard.entries, key)
+			}
+		}
+
+		shard.mu.Unlock()
+	}
+}
+
+// FlushAll evicts every cached decision. RBAC objects (Role, RoleBinding,
+// ClusterRole, ClusterRoleBinding) can change what any number of users are
+// allowed to do, so rather than trying to work out which cached decisions a
+// given binding affects, a mutation to one simply drops the whole cache.
+func (c *AuthzCache) FlushAll() {
+	for _, shard := range c.shards {
+		shard.mu.Lock()
+		shard.entries = make(map[string]*authzCacheEntry)
+		shard.lru = list.New()
+		shard.mu.Unlock()
+	}
+}
+
+// runSweeper periodically scans every shard for expired entries, the same
+// way clientSetCache.runSweeper does, instead of only checking TTLs inline
+// on Get.
+func (c *AuthzCache) runSweeper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.sweepExpired()
+		}
+	}
+}
+
+func (c *AuthzCache) sweepExpired() {
+	now := c.clock.Now()
+
+	for _, shard := range c.shards {
+		shard.mu.Lock()
+
+		for key, entry := range shard.entries {
+			if now.After(entry.decision.ExpiresAt) {
+				shard.lru.Remove(entry.element)
+				delete(shard.entries, key)
+			}
+		}
+
+		shard.mu.Unlock()
+	}
+}
+
+// rbacGVRs are the RBAC resources whose mutation can change what any number
+// of users are allowed to do.
+var rbacGVRs = []schema.GroupVersionResource{ //nolint:gochecknoglobals
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"},
+}
+
+// NewAuthzInvalidationEvictFunc returns an EvictFunc that flushes cache on
+// every event. It is meant for an InformerManager dedicated to watching the
+// RBAC resources listed in rbacGVRs, separately from the one driving the
+// response cache, since an RBAC change and a response-cache-relevant change
+// invalidate different things.
+func NewAuthzInvalidationEvictFunc(cache *AuthzCache) EvictFunc {
+	return func(_ *ResponseCache, _ string, _ schema.GroupVersionResource, _ interface{}) {
+		cache.FlushAll()
+	}
+}
+
+// RegisterRBACInvalidation acquires informers for the RBAC resources on
+// cluster through manager, so that manager's EvictFunc (expected to be one
+// built by NewAuthzInvalidationEvictFunc) runs whenever one changes.
+func RegisterRBACInvalidation(manager *InformerManager, cluster string) error {
+	for _, gvr := range rbacGVRs {
+		if err := manager.Acquire(cluster, gvr); err != nil {
+			return fmt.Errorf("error watching %s for authz cache invalidation: %w", gvr.Resource, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+func TestAuthzCacheDedupesConcurrentLookups(t *testing.T) {
+	var calls int32
+
+	release := make(chan struct{})
+
+	ssar := func(_ string, _ authorizationv1.ResourceAttributes) (bool, string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+
+		return true, "allowed", nil
+	}
+
+	c := NewAuthzCache(ssar, AuthzCacheOptions{SweepInterval: time.Hour})
+	defer c.Stop()
+
+	attrs := authorizationv1.ResourceAttributes{Verb: "get", Resource: "pods", Namespace: "default"}
+
+	const lookups = 10
+
+	var wg sync.WaitGroup
+
+	wg.Add(lookups)
+
+	for i := 0; i < lookups; i++ {
+		go func() {
+			defer wg.Done()
+
+			allowed, _, err := c.Allowed("token", attrs)
+			if err != nil || !allowed {
+				t.Errorf("Allowed() = %v, %v, want true, nil", allowed, err)
+			}
+		}()
+	}
+
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("issueSSAR called %d times, want exactly 1 for concurrent lookups of the same key", got)
+	}
+}
+
+func TestAuthzCacheTTLExpiry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+
+	var calls int32
+
+	ssar := func(_ string, _ authorizationv1.ResourceAttributes) (bool, string, error) {
+		atomic.AddInt32(&calls, 1)
+		return true, "", nil
+	}
+
+	c := NewAuthzCache(ssar, AuthzCacheOptions{
+		PositiveTTL: time.Minute, SweepInterval: time.Hour, Clock: clock,
+	})
+	defer c.Stop()
+
+	attrs := authorizationv1.ResourceAttributes{Verb: "get", Resource: "pods"}
+
+	if _, _, err := c.Allowed("token", attrs); err != nil {
+		t.Fatalf("Allowed() error = %v", err)
+	}
+
+	if _, _, err := c.Allowed("token", attrs); err != nil {
+		t.Fatalf("Allowed() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("issueSSAR called %d times, want 1 while the decision is still live", got)
+	}
+
+	clock.now = clock.now.Add(2 * time.Minute)
+
+	if _, _, err := c.Allowed("token", attrs); err != nil {
+		t.Fatalf("Allowed() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("issueSSAR called %d times, want a fresh call once the decision expired", got)
+	}
+}
+
+func TestAuthzCacheFlushToken(t *testing.T) {
+	ssar := func(_ string, _ authorizationv1.ResourceAttributes) (bool, string, error) {
+		return true, "", nil
+	}
+
+	c := NewAuthzCache(ssar, AuthzCacheOptions{SweepInterval: time.Hour})
+	defer c.Stop()
+
+	attrs := authorizationv1.ResourceAttributes{Verb: "get", Resource: "pods"}
+
+	if _, _, err := c.Allowed("token", attrs); err != nil {
+		t.Fatalf("Allowed() error = %v", err)
+	}
+
+	c.FlushToken("token")
+
+	key := authzKey("token", attrs)
+	if _, found := c.get(key); found {
+		t.Errorf("expected FlushToken to evict the cached decision")
+	}
+}
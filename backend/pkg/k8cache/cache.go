@@ -0,0 +1,84 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import "sync"
+
+// CacheEntry is a single cached Kubernetes API response together with the
+// metadata needed to validate or invalidate it later.
+type CacheEntry struct {
+	Body            []byte
+	ResourceVersion string
+}
+
+// ResponseCache stores cached Kubernetes API responses keyed by the cache
+// key built from a request's cluster, GVR, namespace, name and subresource
+// (see RequestInfo.CacheKey). It is
+// the cache that the middleware alongside GetKindAndVerb writes into, and
+// that the informer subsystem in informer.go evicts from on change.
+type ResponseCache struct {
+	mu      sync.RWMutex
+	entries map[string]CacheEntry
+}
+
+// NewResponseCache creates an empty ResponseCache.
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{entries: make(map[string]CacheEntry)}
+}
+
+// Get returns the cached entry for key, if any.
+func (c *ResponseCache) Get(key string) (CacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, found := c.entries[key]
+
+	return entry, found
+}
+
+// Set stores entry under key, overwriting any previous value.
+func (c *ResponseCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+}
+
+// Delete evicts a single key, if present.
+func (c *ResponseCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// DeleteByPrefix evicts every key that starts with prefix. A mutation of a
+// single object needs to invalidate both its own entry and any list entries
+// that would have included it, and those list entries share the object's
+// key prefix (see the cache key builder in requestinfo.go).
+func (c *ResponseCache) DeleteByPrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// defaultResponseCache is a package-wide ResponseCache. No code in this
+// package reads or writes it: ServeFromCache and NewInformerManager both
+// take the ResponseCache they work on as an argument.
+var defaultResponseCache = NewResponseCache() //nolint:gochecknoglobals
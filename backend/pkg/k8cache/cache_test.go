@@ -0,0 +1,42 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import "testing"
+
+func TestDeleteKeyAndChildrenDoesNotCrossInvalidateSiblingNamespaces(t *testing.T) {
+	c := NewResponseCache()
+
+	prodKey := (&RequestInfo{Version: "v1", Resource: "pods", Namespace: "prod"}).CacheKey("cluster")
+	prodObjKey := (&RequestInfo{Version: "v1", Resource: "pods", Namespace: "prod", Name: "web-1"}).CacheKey("cluster")
+	productionKey := (&RequestInfo{Version: "v1", Resource: "pods", Namespace: "production"}).CacheKey("cluster")
+
+	c.Set(prodKey, CacheEntry{ResourceVersion: "1"})
+	c.Set(prodObjKey, CacheEntry{ResourceVersion: "1"})
+	c.Set(productionKey, CacheEntry{ResourceVersion: "1"})
+
+	deleteKeyAndChildren(c, prodKey)
+
+	if _, found := c.Get(prodKey); found {
+		t.Errorf("expected %q to be evicted", prodKey)
+	}
+
+	if _, found := c.Get(prodObjKey); found {
+		t.Errorf("expected %q to be evicted", prodObjKey)
+	}
+
+	if _, found := c.Get(productionKey); !found {
+		t.Errorf("evicting namespace %q must not evict sibling namespace %q", "prod", "production")
+	}
+}
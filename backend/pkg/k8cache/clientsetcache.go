@@ -0,0 +1,254 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import (
+	"container/list"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/client-go/kubernetes"
+)
+
+// clientSetCacheShards is the number of shards ClientSetCache splits its
+// entries across. Each shard has its own lock, so lookups for different
+// cache keys no longer serialize on one global mutex.
+const clientSetCacheShards = 16
+
+const (
+	defaultClientSetCacheMaxEntries = 1000
+	defaultClientSetCacheTTL        = 10 * time.Minute
+	defaultClientSetCacheSweep      = time.Minute
+)
+
+var ( //nolint:gochecknoglobals
+	clientSetCacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "headlamp_clientset_cache_hits_total",
+		Help: "Number of clientset cache lookups that found a live entry.",
+	})
+	clientSetCacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "headlamp_clientset_cache_misses_total",
+		Help: "Number of clientset cache lookups that found no live entry.",
+	})
+	clientSetCacheEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "headlamp_clientset_cache_evictions_total",
+		Help: "Number of clientset cache entries evicted by TTL or LRU.",
+	})
+)
+
+// Clock is the subset of time used by ClientSetCache, so tests can inject a
+// fake clock instead of depending on wall-clock time.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// ClientSetCacheOptions configures NewClientSetCache.
+type ClientSetCacheOptions struct {
+	// MaxEntries is the maximum number of clientsets held per shard before
+	// the least-recently-used entry in that shard is evicted.
+	MaxEntries int
+	// TTL is how long an entry stays live after it was last Set; Get does
+	// not extend it.
+	TTL time.Duration
+	// SweepInterval is how often the background goroutine scans for
+	// expired entries.
+	SweepInterval time.Duration
+	// Clock is used for TTL bookkeeping; defaults to the real wall clock.
+	Clock Clock
+}
+
+// ClientSetCache is a bounded, thread-safe, expiring store of
+// *kubernetes.Clientset keyed by an opaque cache key, modeled on client-go's
+// ThreadSafeStore/ExpirationCache. Entries expire after TTL: Get treats an
+// expired entry as a miss, and a single background sweep removes expired
+// entries from memory. The store is bounded per shard by an LRU policy
+// applied on Set.
+type ClientSetCache interface {
+	// Get returns the cached clientset for key, if it exists and has not
+	// expired.
+	Get(key string) (*kubernetes.Clientset, bool)
+	// Set stores cs under key, evicting the shard's least-recently-used
+	// entry first if the shard is full.
+	Set(key string, cs *kubernetes.Clientset)
+	// Stop halts the background sweep goroutine.
+	Stop()
+}
+
+type clientSetCacheEntry struct {
+	clientset *kubernetes.Clientset
+	expiresAt time.Time
+	element   *list.Element // position in the shard's LRU list
+}
+
+type clientSetCacheShard struct {
+	mu      sync.RWMutex
+	entries map[string]*clientSetCacheEntry
+	lru     *list.List // front = most recently used; back = least
+}
+
+type clientSetCache struct {
+	shards     [clientSetCacheShards]*clientSetCacheShard
+	maxEntries int
+	ttl        time.Duration
+	clock      Clock
+	stopCh     chan struct{}
+}
+
+// NewClientSetCache creates a ClientSetCache configured by opts, applying
+// defaults for any zero-valued fields, and starts its background sweep
+// goroutine.
+func NewClientSetCache(opts ClientSetCacheOptions) ClientSetCache {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultClientSetCacheMaxEntries
+	}
+
+	if opts.TTL <= 0 {
+		opts.TTL = defaultClientSetCacheTTL
+	}
+
+	if opts.SweepInterval <= 0 {
+		opts.SweepInterval = defaultClientSetCacheSweep
+	}
+
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+
+	c := &clientSetCache{
+		maxEntries: opts.MaxEntries,
+		ttl:        opts.TTL,
+		clock:      opts.Clock,
+		stopCh:     make(chan struct{}),
+	}
+
+	for i := range c.shards {
+		c.shards[i] = &clientSetCacheShard{
+			entries: make(map[string]*clientSetCacheEntry),
+			lru:     list.New(),
+		}
+	}
+
+	go c.runSweeper(opts.SweepInterval)
+
+	return c
+}
+
+func (c *clientSetCache) shardFor(key string) *clientSetCacheShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return c.shards[h.Sum32()%clientSetCacheShards]
+}
+
+func (c *clientSetCache) Get(key string) (*kubernetes.Clientset, bool) {
+	shard := c.shardFor(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	entry, found := shard.entries[key]
+	if !found || c.clock.Now().After(entry.expiresAt) {
+		clientSetCacheMisses.Inc()
+		return nil, false
+	}
+
+	shard.lru.MoveToFront(entry.element)
+	clientSetCacheHits.Inc()
+
+	return entry.clientset, true
+}
+
+func (c *clientSetCache) Set(key string, cs *kubernetes.Clientset) {
+	shard := c.shardFor(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if existing, found := shard.entries[key]; found {
+		existing.clientset = cs
+		existing.expiresAt = c.clock.Now().Add(c.ttl)
+		shard.lru.MoveToFront(existing.element)
+
+		return
+	}
+
+	element := shard.lru.PushFront(key)
+	shard.entries[key] = &clientSetCacheEntry{
+		clientset: cs,
+		expiresAt: c.clock.Now().Add(c.ttl),
+		element:   element,
+	}
+
+	if shard.lru.Len() > c.maxEntries {
+		c.evictOldest(shard)
+	}
+}
+
+// evictOldest removes the shard's least-recently-used entry. Callers must
+// hold shard.mu.
+func (c *clientSetCache) evictOldest(shard *clientSetCacheShard) {
+	oldest := shard.lru.Back()
+	if oldest == nil {
+		return
+	}
+
+	shard.lru.Remove(oldest)
+	delete(shard.entries, oldest.Value.(string)) //nolint:forcetypeassert
+	clientSetCacheEvictions.Inc()
+}
+
+func (c *clientSetCache) Stop() {
+	close(c.stopCh)
+}
+
+// runSweeper periodically scans every shard for expired entries so that
+// they are removed even when no Get for their key ever comes again.
+func (c *clientSetCache) runSweeper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.sweepExpired()
+		}
+	}
+}
+
+func (c *clientSetCache) sweepExpired() {
+	now := c.clock.Now()
+
+	for _, shard := range c.shards {
+		shard.mu.Lock()
+
+		for key, entry := range shard.entries {
+			if now.After(entry.expiresAt) {
+				shard.lru.Remove(entry.element)
+				delete(shard.entries, key)
+				clientSetCacheEvictions.Inc()
+			}
+		}
+
+		shard.mu.Unlock()
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// fakeClock lets tests control TTL expiry deterministically instead of
+// depending on wall-clock time.
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time { return f.now }
+
+func TestClientSetCacheGetSetAndTTLExpiry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	c := NewClientSetCache(ClientSetCacheOptions{TTL: time.Minute, SweepInterval: time.Hour, Clock: clock})
+	defer c.Stop()
+
+	cs := &kubernetes.Clientset{}
+	c.Set("key", cs)
+
+	if got, found := c.Get("key"); !found || got != cs {
+		t.Fatalf("Get() = %v, %v, want %v, true", got, found, cs)
+	}
+
+	clock.now = clock.now.Add(2 * time.Minute)
+
+	if _, found := c.Get("key"); found {
+		t.Errorf("expected entry to have expired after TTL elapsed")
+	}
+}
+
+func TestClientSetCacheSweepRemovesExpiredEntries(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	c, ok := NewClientSetCache(ClientSetCacheOptions{TTL: time.Minute, SweepInterval: time.Hour, Clock: clock}).(*clientSetCache)
+	if !ok {
+		t.Fatal("NewClientSetCache did not return *clientSetCache")
+	}
+	defer c.Stop()
+
+	c.Set("key", &kubernetes.Clientset{})
+	clock.now = clock.now.Add(2 * time.Minute)
+
+	c.sweepExpired()
+
+	shard := c.shardFor("key")
+
+	shard.mu.RLock()
+	_, found := shard.entries["key"]
+	shard.mu.RUnlock()
+
+	if found {
+		t.Errorf("expected sweepExpired to remove the expired entry instead of waiting for the next Get")
+	}
+}
+
+func TestClientSetCacheLRUEviction(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	c, ok := NewClientSetCache(
+		ClientSetCacheOptions{MaxEntries: 1, TTL: time.Hour, SweepInterval: time.Hour, Clock: clock},
+	).(*clientSetCache)
+	if !ok {
+		t.Fatal("NewClientSetCache did not return *clientSetCache")
+	}
+	defer c.Stop()
+
+	older, newer := twoKeysInSameShard(c)
+
+	c.Set(older, &kubernetes.Clientset{})
+	c.Set(newer, &kubernetes.Clientset{})
+
+	if _, found := c.Get(older); found {
+		t.Errorf("expected %q to be evicted once its shard exceeded MaxEntries", older)
+	}
+
+	if _, found := c.Get(newer); !found {
+		t.Errorf("expected %q to remain cached", newer)
+	}
+}
+
+// twoKeysInSameShard returns two distinct keys that hash into the same
+// shard of c, so an LRU eviction test can force a collision deterministically.
+func twoKeysInSameShard(c *clientSetCache) (string, string) {
+	byShard := make(map[*clientSetCacheShard]string)
+
+	for i := 0; ; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		shard := c.shardFor(key)
+
+		if first, found := byShard[shard]; found {
+			return first, key
+		}
+
+		byShard[shard] = key
+	}
+}
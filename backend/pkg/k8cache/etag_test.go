@@ -0,0 +1,91 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestServeFromCacheMiss(t *testing.T) {
+	c := NewResponseCache()
+	info := &RequestInfo{Version: "v1", Resource: "pods", Namespace: "default", Name: "web-1"}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if ServeFromCache(w, r, c, nil, "cluster", info) {
+		t.Fatalf("ServeFromCache() = true on an empty cache, want false")
+	}
+}
+
+func TestServeFromCacheWritesETagOnHit(t *testing.T) {
+	c := NewResponseCache()
+	info := &RequestInfo{Version: "v1", Resource: "pods", Namespace: "default", Name: "web-1"}
+	c.Set(info.CacheKey("cluster"), CacheEntry{Body: []byte(`{"kind":"Pod"}`), ResourceVersion: "42"})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if !ServeFromCache(w, r, c, nil, "cluster", info) {
+		t.Fatalf("ServeFromCache() = false on a cache hit, want true")
+	}
+
+	if got, want := w.Header().Get("ETag"), `"42"`; got != want {
+		t.Errorf("ETag header = %q, want %q", got, want)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestServeFromCacheReturns304WhenIfNoneMatchMatches(t *testing.T) {
+	c := NewResponseCache()
+	info := &RequestInfo{Version: "v1", Resource: "pods", Namespace: "default", Name: "web-1"}
+	c.Set(info.CacheKey("cluster"), CacheEntry{Body: []byte(`{"kind":"Pod"}`), ResourceVersion: "42"})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", `"42"`)
+
+	if !ServeFromCache(w, r, c, nil, "cluster", info) {
+		t.Fatalf("ServeFromCache() = false, want true")
+	}
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+}
+
+func TestServeFromCacheListStaleAgainstInformerIsTreatedAsMiss(t *testing.T) {
+	c := NewResponseCache()
+	info := &RequestInfo{Version: "v1", Resource: "pods", Namespace: "default", IsList: true}
+	c.Set(info.CacheKey("cluster"), CacheEntry{Body: []byte(`{"kind":"PodList"}`), ResourceVersion: "10"})
+
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	manager := NewInformerManager(c, nil, nil)
+	manager.informers[gvrKey{cluster: "cluster", gvr: gvr}] = &watchedInformer{rv: "11"}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if ServeFromCache(w, r, c, manager, "cluster", info) {
+		t.Errorf("ServeFromCache() = true for a list whose cached rv trails the informer, want false")
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubernetes-sigs/headlamp/backend/pkg/logger"
+)
+
+// informerIdleGrace is how long an informer is kept running after its last
+// cache key stops referencing it, in case another request needs it again
+// shortly after.
+const informerIdleGrace = 2 * time.Minute
+
+// gvrKey identifies a single watched resource within a single cluster.
+type gvrKey struct {
+	cluster string
+	gvr     schema.GroupVersionResource
+}
+
+// EvictFunc computes the response cache keys affected by a change to obj
+// and evicts them. Namespaced resources and cluster-scoped resources evict
+// differently (a namespaced list key includes the namespace, a
+// cluster-scoped one doesn't), so the caller passes an EvictFunc to
+// NewInformerManager instead of this package hard-coding one. A manager
+// calls its single EvictFunc for every GVR it watches, passing the GVR.
+type EvictFunc func(respCache *ResponseCache, cluster string, gvr schema.GroupVersionResource, obj interface{})
+
+// watchedInformer tracks one shared informer along with how many cache keys
+// currently reference it, so it can be stopped once nothing does.
+type watchedInformer struct {
+	informer  cache.SharedIndexInformer
+	stopCh    chan struct{}
+	refCount  int
+	lastUnref time.Time
+	rv        string
+}
+
+// InformerManager lazily starts one shared informer per (cluster, GVR) that
+// the response cache serves. Its Add/Update/Delete handlers evict the
+// affected cache keys immediately instead of waiting on a TTL, and it
+// tracks each informer's resourceVersion so GET responses can be tagged
+// with it and list responses validated against the informer store.
+type InformerManager struct {
+	mu          sync.Mutex
+	informers   map[gvrKey]*watchedInformer
+	respCache   *ResponseCache
+	evict       EvictFunc
+	dynamicFor  func(cluster string) (dynamic.Interface, error)
+	sweeperOnce sync.Once
+}
+
+// NewInformerManager creates an InformerManager that evicts from respCache
+// using evict, and builds a dynamic client for a cluster on first use via
+// dynamicFor.
+func NewInformerManager(
+	respCache *ResponseCache, evict EvictFunc, dynamicFor func(cluster string) (dynamic.Interface, error),
+) *InformerManager {
+	return &InformerManager{
+		informers:  make(map[gvrKey]*watchedInformer),
+		respCache:  respCache,
+		evict:      evict,
+		dynamicFor: dynamicFor,
+	}
+}
+
+// Acquire ensures a shared informer is running for (cluster, gvr), starting
+// one on first use, and increments its reference count. Release must be
+// called once the caller's cache key no longer depends on this GVR.
+//
+// The informer always watches the whole cluster rather than the namespace
+// of whichever caller happens to acquire it first: (cluster, gvr) is a
+// single cache entry shared by every namespace's requests for that
+// resource, so a namespace-scoped informer would silently miss changes
+// made in any other namespace for as long as it stayed alive. Callers that
+// need namespace-scoped invalidation filter at the cache-key/evict layer
+// (see DefaultEvictFunc) instead.
+func (m *InformerManager) Acquire(cluster string, gvr schema.GroupVersionResource) error {
+	m.sweeperOnce.Do(m.startSweeper)
+
+	key := gvrKey{cluster: cluster, gvr: gvr}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if wi, found := m.informers[key]; found {
+		wi.refCount++
+		return nil
+	}
+
+	client, err := m.dynamicFor(cluster)
+	if err != nil {
+		return fmt.Errorf("error creating dynamic client for cluster %q: %w", cluster, err)
+	}
+
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, 0, metav1.NamespaceAll, nil)
+	informer := factory.ForResource(gvr).Informer()
+
+	wi := &watchedInformer{
+		informer: informer,
+		stopCh:   make(chan struct{}),
+		refCount: 1,
+	}
+
+	handlers := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { m.handleEvent(key, wi, obj) },
+		UpdateFunc: func(_, obj interface{}) { m.handleEvent(key, wi, obj) },
+		DeleteFunc: func(obj interface{}) { m.handleEvent(key, wi, obj) },
+	}
+
+	if _, err := informer.AddEventHandler(handlers); err != nil {
+		return fmt.Errorf("error registering event handler for %s in cluster %q: %w", gvr, cluster, err)
+	}
+
+	m.informers[key] = wi
+
+	go informer.Run(wi.stopCh)
+
+	return nil
+}
+
+// Release decrements the reference count of the informer backing
+// (cluster, gvr). The informer keeps running for informerIdleGrace in case
+// a new cache key references it again shortly after.
+func (m *InformerManager) Release(cluster string, gvr schema.GroupVersionResource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wi, found := m.informers[gvrKey{cluster: cluster, gvr: gvr}]
+	if !found {
+		return
+	}
+
+	wi.refCount--
+	if wi.refCount <= 0 {
+		wi.refCount = 0
+		wi.lastUnref = time.Now()
+	}
+}
+
+// LastResourceVersion returns the most recent resourceVersion observed by
+// the informer backing (cluster, gvr), or "" if no informer is running.
+func (m *InformerManager) LastResourceVersion(cluster string, gvr schema.GroupVersionResource) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wi, found := m.informers[gvrKey{cluster: cluster, gvr: gvr}]
+	if !found {
+		return ""
+	}
+
+	return wi.rv
+}
+
+// handleEvent evicts the cache keys affected by obj and records the
+// informer's resourceVersion. obj is unwrapped from a
+// cache.DeletedFinalStateUnknown tombstone first, which DeleteFunc receives
+// whenever the watch is disrupted and the reflector only notices the
+// deletion on relist, so the evict callback always sees the real object.
+func (m *InformerManager) handleEvent(key gvrKey, wi *watchedInformer, obj interface{}) {
+	obj = unwrapTombstone(obj)
+
+	if accessor, err := metav1ObjectAccessor(obj); err == nil {
+		m.mu.Lock()
+		wi.rv = accessor.GetResourceVersion()
+		m.mu.Unlock()
+	}
+
+	if m.evict != nil {
+		m.evict(m.respCache, key.cluster, key.gvr, obj)
+	}
+}
+
+// startSweeper launches the single background goroutine that stops
+// informers which have been unreferenced for longer than informerIdleGrace.
+func (m *InformerManager) startSweeper() {
+	go func() {
+		ticker := time.NewTicker(informerIdleGrace)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			m.sweepIdle()
+		}
+	}()
+}
+
+func (m *InformerManager) sweepIdle() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	for key, wi := range m.informers {
+		if wi.refCount == 0 && now.Sub(wi.lastUnref) > informerIdleGrace {
+			close(wi.stopCh)
+			delete(m.informers, key)
+			logger.Log(logger.LevelInfo, nil, nil, fmt.Sprintf("stopped idle informer for %s in cluster %s", key.gvr, key.cluster))
+		}
+	}
+}
+
+// unwrapTombstone unwraps obj from a cache.DeletedFinalStateUnknown, the
+// tombstone cache.ResourceEventHandler.OnDelete documents receiving instead
+// of the real object when a watch is disrupted and the deletion is only
+// noticed on relist. obj is returned unchanged otherwise.
+func unwrapTombstone(obj interface{}) interface{} {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return tombstone.Obj
+	}
+
+	return obj
+}
+
+// metav1ObjectAccessor adapts an informer event's obj to metav1.Object so
+// its resourceVersion can be read regardless of the concrete unstructured
+// type involved.
+func metav1ObjectAccessor(obj interface{}) (metav1.Object, error) {
+	obj = unwrapTombstone(obj)
+
+	accessor, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, fmt.Errorf("object %T does not implement metav1.Object", obj)
+	}
+
+	return accessor, nil
+}
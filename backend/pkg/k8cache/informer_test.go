@@ -0,0 +1,203 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+// evictCall records one invocation of an EvictFunc so tests can assert on
+// what InformerManager passed through.
+type evictCall struct {
+	cluster string
+	gvr     schema.GroupVersionResource
+	obj     interface{}
+}
+
+// newTestManager returns an InformerManager backed by a fake dynamic client
+// for gvr, plus a channel that records every EvictFunc invocation.
+func newTestManager(gvr schema.GroupVersionResource) (*InformerManager, chan evictCall) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "PodList"},
+	)
+
+	evicted := make(chan evictCall, 10)
+	evict := func(_ *ResponseCache, cluster string, gvr schema.GroupVersionResource, obj interface{}) {
+		evicted <- evictCall{cluster: cluster, gvr: gvr, obj: obj}
+	}
+
+	manager := NewInformerManager(NewResponseCache(), evict, func(string) (dynamic.Interface, error) {
+		return client, nil
+	})
+
+	return manager, evicted
+}
+
+func TestInformerManagerAcquireReleaseRefCounting(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	manager, _ := newTestManager(gvr)
+
+	if err := manager.Acquire("cluster", gvr); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	key := gvrKey{cluster: "cluster", gvr: gvr}
+
+	manager.mu.Lock()
+	wi := manager.informers[key]
+	manager.mu.Unlock()
+
+	if wi == nil {
+		t.Fatal("expected an informer to be registered after Acquire")
+	}
+
+	if wi.refCount != 1 {
+		t.Errorf("refCount = %d, want 1", wi.refCount)
+	}
+
+	if err := manager.Acquire("cluster", gvr); err != nil {
+		t.Fatalf("second Acquire() error = %v", err)
+	}
+
+	if wi.refCount != 2 {
+		t.Errorf("refCount after second Acquire = %d, want 2", wi.refCount)
+	}
+
+	manager.Release("cluster", gvr)
+
+	if wi.refCount != 1 {
+		t.Errorf("refCount after first Release = %d, want 1", wi.refCount)
+	}
+
+	manager.Release("cluster", gvr)
+
+	if wi.refCount != 0 {
+		t.Errorf("refCount after second Release = %d, want 0", wi.refCount)
+	}
+
+	if wi.lastUnref.IsZero() {
+		t.Error("expected lastUnref to be set once refCount reached 0")
+	}
+}
+
+func TestInformerManagerSweepIdleStopsUnreferencedInformers(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	manager, _ := newTestManager(gvr)
+
+	if err := manager.Acquire("cluster", gvr); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	manager.Release("cluster", gvr)
+
+	key := gvrKey{cluster: "cluster", gvr: gvr}
+
+	manager.mu.Lock()
+	manager.informers[key].lastUnref = time.Now().Add(-2 * informerIdleGrace)
+	manager.mu.Unlock()
+
+	manager.sweepIdle()
+
+	manager.mu.Lock()
+	_, found := manager.informers[key]
+	manager.mu.Unlock()
+
+	if found {
+		t.Error("expected sweepIdle to stop and remove the idle informer")
+	}
+}
+
+func TestInformerManagerSweepIdleLeavesReferencedInformers(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	manager, _ := newTestManager(gvr)
+
+	if err := manager.Acquire("cluster", gvr); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	manager.sweepIdle()
+
+	key := gvrKey{cluster: "cluster", gvr: gvr}
+
+	manager.mu.Lock()
+	_, found := manager.informers[key]
+	manager.mu.Unlock()
+
+	if !found {
+		t.Error("expected sweepIdle to leave a still-referenced informer running")
+	}
+}
+
+func TestInformerManagerHandleEventInvokesEvictFuncAndTracksResourceVersion(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	manager, evicted := newTestManager(gvr)
+
+	key := gvrKey{cluster: "cluster", gvr: gvr}
+	wi := &watchedInformer{}
+
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web-1", "namespace": "default", "resourceVersion": "7"},
+	}}
+
+	manager.handleEvent(key, wi, pod)
+
+	if wi.rv != "7" {
+		t.Errorf("rv = %q, want %q", wi.rv, "7")
+	}
+
+	select {
+	case call := <-evicted:
+		if call.cluster != "cluster" || call.gvr != gvr {
+			t.Errorf("evict called with cluster=%q gvr=%v, want cluster=%q gvr=%v", call.cluster, call.gvr, "cluster", gvr)
+		}
+	default:
+		t.Fatal("expected EvictFunc to be invoked for an Add/Update event")
+	}
+}
+
+func TestInformerManagerHandleEventUnwrapsDeletedFinalStateUnknown(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	manager, evicted := newTestManager(gvr)
+
+	key := gvrKey{cluster: "cluster", gvr: gvr}
+	wi := &watchedInformer{}
+
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web-1", "namespace": "default", "resourceVersion": "9"},
+	}}
+	tombstone := cache.DeletedFinalStateUnknown{Key: "default/web-1", Obj: pod}
+
+	manager.handleEvent(key, wi, tombstone)
+
+	if wi.rv != "9" {
+		t.Errorf("rv = %q, want %q (tombstone should have been unwrapped)", wi.rv, "9")
+	}
+
+	select {
+	case call := <-evicted:
+		if _, stillWrapped := call.obj.(cache.DeletedFinalStateUnknown); stillWrapped {
+			t.Error("expected EvictFunc to receive the unwrapped object, not the tombstone")
+		}
+	default:
+		t.Fatal("expected EvictFunc to be invoked for a DeletedFinalStateUnknown tombstone")
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RequestInfo describes a parsed Kubernetes API request, mirroring the
+// fields kube-apiserver's own request-info resolver produces. GetKindAndVerb
+// used to return just the last path segment as "kind", which is wrong for
+// almost every real request; ParseRequestInfo walks the path against the
+// `/api/v1/...` and `/apis/{group}/{version}/...` grammars instead.
+type RequestInfo struct {
+	Group       string
+	Version     string
+	Resource    string
+	Subresource string
+	Namespace   string
+	Name        string
+	Verb        string
+	IsList      bool
+	IsWatch     bool
+}
+
+// ParseRequestInfo parses apiPath (the "api" mux var holding everything
+// after the proxy prefix, e.g. "api/v1/namespaces/foo/pods/bar/log" or
+// "apis/apps/v1/deployments") together with the HTTP method and the
+// "watch" query parameter into a RequestInfo.
+func ParseRequestInfo(apiPath, method string, isWatchParam bool) *RequestInfo {
+	parts := strings.Split(strings.Trim(apiPath, "/"), "/")
+
+	info := &RequestInfo{}
+
+	switch {
+	case len(parts) > 0 && parts[0] == "api":
+		// /api/{version}/... (core group, group is empty)
+		if len(parts) < 2 {
+			return info
+		}
+
+		info.Version = parts[1]
+		parseResourcePath(info, parts[2:])
+	case len(parts) > 0 && parts[0] == "apis":
+		// /apis/{group}/{version}/...
+		if len(parts) < 3 {
+			return info
+		}
+
+		info.Group = parts[1]
+		info.Version = parts[2]
+		parseResourcePath(info, parts[3:])
+	}
+
+	info.Verb = resolveVerb(method, info.Name, isWatchParam)
+	info.IsWatch = info.Verb == "watch"
+	info.IsList = info.Verb == "list"
+
+	return info
+}
+
+// parseResourcePath fills in namespace, resource, name and subresource from
+// the path segments that follow the group/version, handling both
+// "namespaces/{ns}/{resource}/{name}/{subresource}" and the cluster-scoped
+// "{resource}/{name}/{subresource}" forms.
+//
+// "namespaces" is also a resource in its own right (you can list, get,
+// update or delete a namespace), so a path starting with "namespaces" is
+// only the namespace-prefix form when it is followed by a resource other
+// than one of the namespace object's own subresources; otherwise the
+// request is against the namespaces resource itself, e.g.
+// "namespaces" (list), "namespaces/foo" (get) or "namespaces/foo/status".
+func parseResourcePath(info *RequestInfo, parts []string) {
+	if len(parts) == 0 {
+		return
+	}
+
+	if parts[0] == "namespaces" {
+		switch {
+		case len(parts) == 1:
+			info.Resource = "namespaces"
+			return
+		case len(parts) == 2:
+			info.Resource = "namespaces"
+			info.Name = parts[1]
+
+			return
+		case len(parts) == 3 && isNamespaceSubresource(parts[2]):
+			info.Resource = "namespaces"
+			info.Name = parts[1]
+			info.Subresource = parts[2]
+
+			return
+		default:
+			info.Namespace = parts[1]
+			parts = parts[2:]
+		}
+	}
+
+	info.Resource = parts[0]
+
+	if len(parts) > 1 {
+		info.Name = parts[1]
+	}
+
+	if len(parts) > 2 {
+		info.Subresource = strings.Join(parts[2:], "/")
+	}
+}
+
+// isNamespaceSubresource reports whether segment is one of the namespace
+// object's own subresources, as opposed to the start of a namespaced
+// resource path (e.g. "namespaces/foo/pods").
+func isNamespaceSubresource(segment string) bool {
+	switch segment {
+	case "status", "finalize":
+		return true
+	default:
+		return false
+	}
+}
+
+// resolveVerb maps an HTTP method (plus whether ?watch=true was set and
+// whether a resource name is present) onto a Kubernetes verb, the same way
+// kube-apiserver's request-info resolver does.
+func resolveVerb(method, name string, isWatch bool) string {
+	switch method {
+	case http.MethodGet:
+		if isWatch {
+			return "watch"
+		}
+
+		if name == "" {
+			return "list"
+		}
+
+		return "get"
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		if name == "" {
+			return "deletecollection"
+		}
+
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// GetRequestInfo extracts a RequestInfo from the incoming HTTP request's
+// "api" mux var.
+func GetRequestInfo(r *http.Request) *RequestInfo {
+	apiPath, ok := mux.Vars(r)["api"]
+	if !ok || apiPath == "" {
+		return &RequestInfo{Verb: "unknown"}
+	}
+
+	isWatch, _ := strconv.ParseBool(r.URL.Query().Get("watch"))
+
+	return ParseRequestInfo(apiPath, r.Method, isWatch)
+}
+
+// CacheKey builds the response cache key for a parsed request on a given
+// cluster. List keys (no Name) and single-object keys share the
+// {cluster}/{group}/{version}/{resource} prefix, with the namespace and
+// name appended when present, so DeleteByPrefix can invalidate a namespace's
+// list alongside one of its objects without needing a second lookup.
+func (info *RequestInfo) CacheKey(cluster string) string {
+	key := strings.Join([]string{cluster, info.Group, info.Version, info.Resource}, "/")
+
+	if info.Namespace != "" {
+		key += "/" + info.Namespace
+	}
+
+	if info.Name != "" {
+		key += "/" + info.Name
+	}
+
+	if info.Subresource != "" {
+		key += "/" + info.Subresource
+	}
+
+	return key
+}
+
+// DefaultEvictFunc is the EvictFunc used when the middleware doesn't need
+// anything more specific. It evicts the object's own cache entry (with its
+// subresource entries) plus the list entries that would have included it,
+// using RequestInfo.CacheKey's layout so it stays in sync with the cache key
+// builder: a mutation of pods/bar in namespace ns invalidates ns's pod list
+// and the cluster-wide pod list. Entries for other objects are kept.
+func DefaultEvictFunc(respCache *ResponseCache, cluster string, gvr schema.GroupVersionResource, obj interface{}) {
+	accessor, err := metav1ObjectAccessor(obj)
+	if err != nil {
+		return
+	}
+
+	info := &RequestInfo{
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+		Namespace: accessor.GetNamespace(),
+		Name:      accessor.GetName(),
+	}
+
+	deleteKeyAndChildren(respCache, info.CacheKey(cluster))
+
+	// List keys are prefixes of every object key under them, so they are
+	// deleted exactly rather than with their children.
+	listInfo := &RequestInfo{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource}
+	respCache.Delete(listInfo.CacheKey(cluster))
+
+	if info.Namespace != "" {
+		nsListInfo := &RequestInfo{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource, Namespace: info.Namespace}
+		respCache.Delete(nsListInfo.CacheKey(cluster))
+	}
+}
+
+// deleteKeyAndChildren evicts key itself plus every key nested under it
+// (key + "/..."). CacheKey segments are joined with "/" but stored without
+// a trailing separator, so DeleteByPrefix(key) alone would also match a
+// sibling whose name happens to have key's last segment as a string
+// prefix (e.g. evicting namespace "prod" would also catch "production");
+// appending the separator before matching avoids that.
+func deleteKeyAndChildren(respCache *ResponseCache, key string) {
+	respCache.Delete(key)
+	respCache.DeleteByPrefix(key + "/")
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8cache
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseRequestInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiPath string
+		method  string
+		isWatch bool
+		want    RequestInfo
+	}{
+		{
+			name:    "core group get",
+			apiPath: "api/v1/namespaces/foo/pods/bar",
+			method:  http.MethodGet,
+			want: RequestInfo{
+				Version: "v1", Resource: "pods", Namespace: "foo", Name: "bar", Verb: "get",
+			},
+		},
+		{
+			name:    "core group subresource",
+			apiPath: "api/v1/namespaces/foo/pods/bar/log",
+			method:  http.MethodGet,
+			want: RequestInfo{
+				Version: "v1", Resource: "pods", Namespace: "foo", Name: "bar", Subresource: "log", Verb: "get",
+			},
+		},
+		{
+			name:    "named group list",
+			apiPath: "apis/apps/v1/deployments",
+			method:  http.MethodGet,
+			want: RequestInfo{
+				Group: "apps", Version: "v1", Resource: "deployments", Verb: "list", IsList: true,
+			},
+		},
+		{
+			name:    "namespaced list",
+			apiPath: "api/v1/namespaces/foo/pods",
+			method:  http.MethodGet,
+			want: RequestInfo{
+				Version: "v1", Resource: "pods", Namespace: "foo", Verb: "list", IsList: true,
+			},
+		},
+		{
+			name:    "list namespaces",
+			apiPath: "api/v1/namespaces",
+			method:  http.MethodGet,
+			want: RequestInfo{
+				Version: "v1", Resource: "namespaces", Verb: "list", IsList: true,
+			},
+		},
+		{
+			name:    "get a namespace",
+			apiPath: "api/v1/namespaces/foo",
+			method:  http.MethodGet,
+			want: RequestInfo{
+				Version: "v1", Resource: "namespaces", Name: "foo", Verb: "get",
+			},
+		},
+		{
+			name:    "namespace subresource",
+			apiPath: "api/v1/namespaces/foo/status",
+			method:  http.MethodPut,
+			want: RequestInfo{
+				Version: "v1", Resource: "namespaces", Name: "foo", Subresource: "status", Verb: "update",
+			},
+		},
+		{
+			name:    "watch",
+			apiPath: "api/v1/namespaces/foo/pods",
+			method:  http.MethodGet,
+			isWatch: true,
+			want: RequestInfo{
+				Version: "v1", Resource: "pods", Namespace: "foo", Verb: "watch", IsWatch: true,
+			},
+		},
+		{
+			name:    "create",
+			apiPath: "api/v1/namespaces/foo/pods",
+			method:  http.MethodPost,
+			want: RequestInfo{
+				Version: "v1", Resource: "pods", Namespace: "foo", Verb: "create",
+			},
+		},
+		{
+			name:    "patch",
+			apiPath: "api/v1/namespaces/foo/pods/bar",
+			method:  http.MethodPatch,
+			want: RequestInfo{
+				Version: "v1", Resource: "pods", Namespace: "foo", Name: "bar", Verb: "patch",
+			},
+		},
+		{
+			name:    "deletecollection",
+			apiPath: "api/v1/namespaces/foo/pods",
+			method:  http.MethodDelete,
+			want: RequestInfo{
+				Version: "v1", Resource: "pods", Namespace: "foo", Verb: "deletecollection", IsList: false,
+			},
+		},
+		{
+			name:    "delete",
+			apiPath: "api/v1/namespaces/foo/pods/bar",
+			method:  http.MethodDelete,
+			want: RequestInfo{
+				Version: "v1", Resource: "pods", Namespace: "foo", Name: "bar", Verb: "delete",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseRequestInfo(tt.apiPath, tt.method, tt.isWatch)
+			if *got != tt.want {
+				t.Errorf("ParseRequestInfo(%q, %q, %v) = %+v, want %+v", tt.apiPath, tt.method, tt.isWatch, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestInfoCacheKey(t *testing.T) {
+	got := (&RequestInfo{Version: "v1", Resource: "pods", Namespace: "prod", Name: "web-1"}).CacheKey("cluster")
+
+	want := "cluster//v1/pods/prod/web-1"
+	if got != want {
+		t.Errorf("CacheKey() = %q, want %q", got, want)
+	}
+}